@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AlertRule is one named threshold check, loaded from the -alert-count /
+// -alert-rate flags or from the alerts.yml config file. A rule fires when
+// its Metric (the total count, or the rate per minute, of matching
+// MessageGroups) satisfies Op against Threshold.
+type AlertRule struct {
+	Name         string
+	Level        string // exact level filter, e.g. "ERRO"; empty means any
+	MessageRegex *regexp.Regexp
+	Metric       string // "count" or "rate"
+	Op           string // ">", ">=", "<", "<=", "=="
+	Threshold    float64
+	Webhook      string // Slack-compatible or generic webhook URL
+	Command      string // e.g. "notify-send"
+}
+
+var reInlineRule = regexp.MustCompile(`^([A-Za-z]+)\s*(>=|<=|==|>|<)\s*([\d.]+)(?:/min)?$`)
+
+// parseCountRules parses a -alert-count flag value like "ERRO>=5,CRIT>=1"
+// into count-threshold rules.
+func parseCountRules(spec string) []AlertRule {
+	return parseInlineRules(spec, "count")
+}
+
+// parseRateRules parses a -alert-rate flag value like "WARN>10/min" into
+// rate-threshold rules.
+func parseRateRules(spec string) []AlertRule {
+	return parseInlineRules(spec, "rate")
+}
+
+func parseInlineRules(spec, metric string) []AlertRule {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+	var rules []AlertRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		m := reInlineRule.FindStringSubmatch(part)
+		if m == nil {
+			continue
+		}
+		threshold, _ := strconv.ParseFloat(m[3], 64)
+		rules = append(rules, AlertRule{
+			Name:      fmt.Sprintf("%s:%s", metric, part),
+			Level:     strings.ToUpper(m[1]),
+			Metric:    metric,
+			Op:        m[2],
+			Threshold: threshold,
+		})
+	}
+	return rules
+}
+
+// alertsConfigPath returns the default alerts.yml location,
+// ~/.config/logwizzy/alerts.yml.
+func alertsConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "logwizzy", "alerts.yml"), nil
+}
+
+// compareOp applies a threshold comparison operator.
+func compareOp(v float64, op string, threshold float64) bool {
+	switch op {
+	case ">":
+		return v > threshold
+	case ">=":
+		return v >= threshold
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	case "==":
+		return v == threshold
+	default:
+		return false
+	}
+}
+
+// evalAlertRule matches rule's level/message filters against groups, then
+// computes its metric (total count, or rate per minute, across the
+// matching groups) and reports whether the rule fired.
+func evalAlertRule(rule AlertRule, groups []*MessageGroup) (value float64, fired bool) {
+	var matched []*MessageGroup
+	for _, g := range groups {
+		if rule.Level != "" && g.Level != rule.Level {
+			continue
+		}
+		if rule.MessageRegex != nil && !rule.MessageRegex.MatchString(g.Sample) {
+			continue
+		}
+		matched = append(matched, g)
+	}
+
+	count := 0
+	var times []time.Time
+	for _, g := range matched {
+		count += g.Count
+		times = append(times, g.Times...)
+	}
+
+	if rule.Metric == "rate" {
+		if len(times) == 0 {
+			return 0, false
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		spanMinutes := times[len(times)-1].Sub(times[0]).Minutes()
+		if spanMinutes < 1 {
+			spanMinutes = 1
+		}
+		value = float64(count) / spanMinutes
+	} else {
+		value = float64(count)
+	}
+
+	return value, compareOp(value, rule.Op, rule.Threshold)
+}
+
+// dispatchAlert notifies a fired rule's destination: a Slack-compatible or
+// generic webhook, or a local notify-send-style command.
+func dispatchAlert(rule AlertRule, value float64) error {
+	summary := fmt.Sprintf("LogWizzy alert %q fired: %s %s %g (observed %.2f)",
+		rule.Name, rule.Metric, rule.Op, rule.Threshold, value)
+
+	if rule.Command != "" {
+		return exec.Command(rule.Command, "LogWizzy alert", summary).Run()
+	}
+	if rule.Webhook == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"text": summary})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(rule.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", rule.Webhook, resp.Status)
+	}
+	return nil
+}
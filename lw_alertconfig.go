@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// loadAlertConfig parses LogWizzy's restricted alerts.yml subset: a
+// top-level "rules:" list of flat key/value maps, e.g.
+//
+//	rules:
+//	  - name: too-many-errors
+//	    level: ERRO
+//	    count: ">=5"
+//	    url: https://hooks.slack.com/services/...
+//	  - name: warn-burst
+//	    message: "connection.*"
+//	    rate: ">10/min"
+//	    command: notify-send
+//
+// Full YAML (nested maps, multi-line scalars, anchors, flow style, ...)
+// isn't supported - this is intentionally just enough to express rules.
+func loadAlertConfig(path string) ([]AlertRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []AlertRule
+	var cur map[string]string
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		rule, err := ruleFromFields(cur)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logwizzy: skipping alert rule %q: %v\n", cur["name"], err)
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "rules:" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			flush()
+			cur = map[string]string{}
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+		}
+		if cur == nil {
+			continue
+		}
+
+		key, val, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		cur[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(val), `"'`)
+	}
+	flush()
+
+	return rules, nil
+}
+
+// ruleFromFields converts one flat key/value rule map into an AlertRule. It
+// reports an error instead of panicking if the rule's message regex doesn't
+// compile, so a typo in alerts.yml can't take down the whole process.
+func ruleFromFields(m map[string]string) (AlertRule, error) {
+	r := AlertRule{Name: m["name"], Level: strings.ToUpper(m["level"])}
+	if msg := m["message"]; msg != "" {
+		re, err := regexp.Compile(msg)
+		if err != nil {
+			return AlertRule{}, fmt.Errorf("invalid message regex %q: %w", msg, err)
+		}
+		r.MessageRegex = re
+	}
+	if c, ok := m["count"]; ok {
+		r.Metric = "count"
+		r.Op, r.Threshold = splitThreshold(c)
+	} else if rate, ok := m["rate"]; ok {
+		r.Metric = "rate"
+		r.Op, r.Threshold = splitThreshold(strings.TrimSuffix(rate, "/min"))
+	}
+	r.Webhook = m["url"]
+	r.Command = m["command"]
+	return r, nil
+}
+
+var reThreshold = regexp.MustCompile(`^(>=|<=|==|>|<)\s*([\d.]+)$`)
+
+// splitThreshold parses a threshold expression like ">=5" into its operator
+// and numeric value.
+func splitThreshold(s string) (op string, threshold float64) {
+	m := reThreshold.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return ">=", 0
+	}
+	v, _ := strconv.ParseFloat(m[2], 64)
+	return m[1], v
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// ColorMode controls when LogWizzy emits ANSI color codes.
+type ColorMode string
+
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+// parseColorMode parses a --color flag value, defaulting to auto for
+// anything unrecognized.
+func parseColorMode(s string) ColorMode {
+	switch strings.ToLower(s) {
+	case "always":
+		return ColorAlways
+	case "never":
+		return ColorNever
+	default:
+		return ColorAuto
+	}
+}
+
+// isTerminal reports whether f looks like an interactive terminal rather
+// than a pipe or redirected file.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled decides whether ANSI colors should be used for stdout,
+// honoring --color, NO_COLOR (https://no-color.org), and TTY detection, in
+// that priority order. On Windows it also enables virtual-terminal
+// processing so legacy consoles render the escapes instead of printing
+// them literally.
+func colorEnabled(mode ColorMode) bool {
+	switch mode {
+	case ColorAlways:
+		enableWindowsVT(os.Stdout)
+		return true
+	case ColorNever:
+		return false
+	default:
+		if os.Getenv("NO_COLOR") != "" {
+			return false
+		}
+		if !isTerminal(os.Stdout) {
+			return false
+		}
+		enableWindowsVT(os.Stdout)
+		return true
+	}
+}
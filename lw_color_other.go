@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+import "os"
+
+// enableWindowsVT is a no-op outside Windows; ANSI escapes already work.
+func enableWindowsVT(f *os.File) {}
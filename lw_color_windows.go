@@ -0,0 +1,38 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// enableVirtualTerminalProcessing isn't exposed as a syscall constant; it's
+// documented by Microsoft as console mode flag 0x0004.
+const enableVirtualTerminalProcessing = 0x0004
+
+// syscall's stdlib package only wraps GetConsoleMode, not SetConsoleMode,
+// so the setter is resolved by hand here.
+var (
+	modkernel32        = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = modkernel32.NewProc("SetConsoleMode")
+)
+
+func setConsoleMode(handle syscall.Handle, mode uint32) error {
+	r1, _, err := procSetConsoleMode.Call(uintptr(handle), uintptr(mode))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// enableWindowsVT turns on ANSI escape processing for f's console, for
+// legacy Windows consoles that don't interpret them by default.
+func enableWindowsVT(f *os.File) {
+	handle := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return
+	}
+	_ = setConsoleMode(handle, mode|enableVirtualTerminalProcessing)
+}
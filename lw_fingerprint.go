@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FingerprintMode selects how raw log messages are grouped together.
+type FingerprintMode string
+
+const (
+	FingerprintExact      FingerprintMode = "exact"
+	FingerprintNormalized FingerprintMode = "normalized"
+	FingerprintDrain      FingerprintMode = "drain"
+)
+
+var (
+	rePath   = regexp.MustCompile(`/proc/\d+/`)
+	reQuoted = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	reUUID   = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	reIPv6   = regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b` +
+		`|\b(?:[0-9a-fA-F]{1,4}:){1,7}:(?:[0-9a-fA-F]{1,4}(?::[0-9a-fA-F]{1,4}){0,6})?\b` +
+		`|\b::(?:[0-9a-fA-F]{1,4}:){0,6}[0-9a-fA-F]{1,4}\b`)
+	reIPv4     = regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}\b`)
+	reToken    = regexp.MustCompile(`\w+`)
+	reDigitRun = regexp.MustCompile(`\d+`)
+)
+
+// fingerprint computes the grouping key (and, for drain mode, the
+// templatized sample) for a raw log message under the given mode.
+func fingerprint(msg string, mode FingerprintMode, drain *drainTree) (key, template string) {
+	switch mode {
+	case FingerprintNormalized:
+		return normalize(msg), ""
+	case FingerprintDrain:
+		return drain.match(msg)
+	default:
+		return msg, ""
+	}
+}
+
+// normalize replaces variable tokens in a log message with placeholders so
+// near-duplicate lines (e.g. differing only by IP or PID) group together:
+// digit runs become <N>, IPv4/IPv6 addresses become <IP>, hex sequences of
+// 8+ chars become <HEX>, UUIDs become <UUID>, quoted strings become <STR>,
+// and /proc/<pid>/ paths become /proc/<PID>/.
+func normalize(msg string) string {
+	s := msg
+	s = rePath.ReplaceAllString(s, "/proc/<PID>/")
+	s = reQuoted.ReplaceAllString(s, "<STR>")
+	s = reUUID.ReplaceAllString(s, "<UUID>")
+	s = reIPv6.ReplaceAllString(s, "<IP>")
+	s = reIPv4.ReplaceAllString(s, "<IP>")
+	s = reToken.ReplaceAllStringFunc(s, func(tok string) string {
+		switch {
+		case isHexToken(tok):
+			return "<HEX>"
+		case isDigits(tok):
+			return "<N>"
+		default:
+			return reDigitRun.ReplaceAllString(tok, "<N>")
+		}
+	})
+	return s
+}
+
+// containsDigit reports whether s contains at least one decimal digit. Used
+// during drain tree descent so that any token carrying variable data (an
+// IP, a PID, a hex ID, ...) is quantized to "<*>" rather than only tokens
+// that are purely digits or hex.
+func containsDigit(s string) bool {
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// isDigits reports whether s consists entirely of decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isHexToken reports whether s is a hex sequence of at least 8 characters
+// containing at least one a-f letter (pure digit runs are handled by
+// isDigits instead).
+func isHexToken(s string) bool {
+	if len(s) < 8 {
+		return false
+	}
+	hasLetter := false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+			hasLetter = true
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// drainTree implements a simplified version of the Drain log parsing
+// algorithm: a fixed-depth prefix tree keyed on token count at the root and
+// on the first `depth` tokens below it, with leaf groups merged by
+// token-position similarity once it meets threshold.
+type drainTree struct {
+	depth     int
+	maxChild  int
+	threshold float64
+	roots     map[int]*drainNode
+	nextID    int
+}
+
+// drainNode is one level of the prefix tree; leaf nodes hold the candidate
+// template groups for messages that reached them.
+type drainNode struct {
+	children map[string]*drainNode
+	groups   []*drainGroup
+}
+
+// drainGroup is a candidate template: a sequence of tokens where mismatching
+// positions across merged messages have been replaced with "<*>". id is a
+// stable identity for the group, used as the grouping key so that the
+// key doesn't change as tokens get wildcarded by later merges.
+type drainGroup struct {
+	id     int
+	tokens []string
+}
+
+// newDrainTree builds a drainTree with LogWizzy's default depth, branching
+// factor, and similarity threshold.
+func newDrainTree() *drainTree {
+	return &drainTree{depth: 4, maxChild: 100, threshold: 0.5, roots: map[int]*drainNode{}}
+}
+
+// match finds the best matching template for msg, merging it in if the
+// similarity threshold is met, or creating a new template otherwise. It
+// returns a stable per-group key (for use as the aggregation map key) and
+// the current template string (for use as the MessageGroup's templatized
+// sample).
+func (d *drainTree) match(msg string) (key, template string) {
+	tokens := strings.Fields(msg)
+	n := len(tokens)
+
+	node, ok := d.roots[n]
+	if !ok {
+		node = &drainNode{children: map[string]*drainNode{}}
+		d.roots[n] = node
+	}
+
+	depth := d.depth
+	if depth > n {
+		depth = n
+	}
+	cur := node
+	for i := 0; i < depth; i++ {
+		tok := tokens[i]
+		if containsDigit(tok) {
+			tok = "<*>"
+		}
+		child, ok := cur.children[tok]
+		if !ok {
+			if len(cur.children) >= d.maxChild {
+				tok = "<*>"
+				child, ok = cur.children[tok]
+			}
+			if !ok {
+				child = &drainNode{children: map[string]*drainNode{}}
+				cur.children[tok] = child
+			}
+		}
+		cur = child
+	}
+
+	var best *drainGroup
+	bestSim := -1.0
+	for _, g := range cur.groups {
+		sim := tokenSimilarity(g.tokens, tokens)
+		if sim > bestSim {
+			bestSim = sim
+			best = g
+		}
+	}
+
+	if best != nil && bestSim >= d.threshold {
+		best.tokens = mergeTokens(best.tokens, tokens)
+	} else {
+		d.nextID++
+		best = &drainGroup{id: d.nextID, tokens: append([]string(nil), tokens...)}
+		cur.groups = append(cur.groups, best)
+	}
+
+	return fmt.Sprintf("drain#%d", best.id), strings.Join(best.tokens, " ")
+}
+
+// tokenSimilarity is the fraction of positions that match exactly or are
+// already wildcarded. Messages of different token counts never match.
+func tokenSimilarity(a, b []string) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	matches := 0
+	for i := range a {
+		if a[i] == b[i] || a[i] == "<*>" {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(a))
+}
+
+// mergeTokens replaces any mismatching position between template and tokens
+// with "<*>", widening the template to cover both.
+func mergeTokens(template, tokens []string) []string {
+	merged := make([]string, len(template))
+	for i := range template {
+		if template[i] == tokens[i] || template[i] == "<*>" {
+			merged[i] = template[i]
+		} else {
+			merged[i] = "<*>"
+		}
+	}
+	return merged
+}
@@ -0,0 +1,115 @@
+package main
+
+import "testing"
+
+func TestTokenSimilarity(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{"identical", []string{"a", "b", "c"}, []string{"a", "b", "c"}, 1},
+		{"one mismatch of three", []string{"a", "b", "c"}, []string{"a", "x", "c"}, 2.0 / 3},
+		{"wildcard counts as match", []string{"a", "<*>", "c"}, []string{"a", "x", "c"}, 1},
+		{"different length", []string{"a", "b"}, []string{"a", "b", "c"}, 0},
+		{"both empty", []string{}, []string{}, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := tokenSimilarity(c.a, c.b); got != c.want {
+				t.Errorf("tokenSimilarity(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+			}
+		})
+	}
+}
+
+func TestMergeTokens(t *testing.T) {
+	cases := []struct {
+		name             string
+		template, tokens []string
+		want             []string
+	}{
+		{
+			"mismatch wildcarded",
+			[]string{"connection", "from", "10.0.0.1", "established"},
+			[]string{"connection", "from", "10.0.0.2", "established"},
+			[]string{"connection", "from", "<*>", "established"},
+		},
+		{
+			"already wildcarded stays wildcarded",
+			[]string{"a", "<*>", "c"},
+			[]string{"a", "b", "c"},
+			[]string{"a", "<*>", "c"},
+		},
+		{
+			"exact match unchanged",
+			[]string{"a", "b", "c"},
+			[]string{"a", "b", "c"},
+			[]string{"a", "b", "c"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := mergeTokens(c.template, c.tokens)
+			if len(got) != len(c.want) {
+				t.Fatalf("mergeTokens(%v, %v) = %v, want %v", c.template, c.tokens, got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("mergeTokens(%v, %v) = %v, want %v", c.template, c.tokens, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDrainTreeMatchMergesDigitBearingTokens(t *testing.T) {
+	d := newDrainTree()
+	msgs := []string{
+		"connection from 10.0.0.1 established",
+		"connection from 10.0.0.2 established",
+		"connection from 10.0.0.3 established",
+	}
+
+	var keys []string
+	var lastTemplate string
+	for _, m := range msgs {
+		key, template := d.match(m)
+		keys = append(keys, key)
+		lastTemplate = template
+	}
+
+	for i := 1; i < len(keys); i++ {
+		if keys[i] != keys[0] {
+			t.Fatalf("expected all messages to share a group key, got %v", keys)
+		}
+	}
+	want := "connection from <*> established"
+	if lastTemplate != want {
+		t.Errorf("template = %q, want %q", lastTemplate, want)
+	}
+}
+
+func TestDrainTreeMatchKeyStableAcrossMerge(t *testing.T) {
+	d := newDrainTree()
+	msgs := []string{
+		"foo bar baz qux connection from 10.0.0.1 established",
+		"foo bar baz qux connection from 10.0.0.2 established",
+		"foo bar baz qux connection from 10.0.0.3 established",
+	}
+
+	groups := map[string]int{}
+	for _, m := range msgs {
+		key, _ := d.match(m)
+		groups[key]++
+	}
+
+	if len(groups) != 1 {
+		t.Fatalf("expected a single group, got %v", groups)
+	}
+	for _, count := range groups {
+		if count != len(msgs) {
+			t.Errorf("group count = %d, want %d", count, len(msgs))
+		}
+	}
+}
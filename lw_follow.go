@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// runFollow streams entries from src and repeatedly redraws a live top-N
+// summary table, instead of LogWizzy's usual read-to-EOF-then-sort pass.
+// Entries are grouped the same way the batch path groups them, via
+// fingerprint/fpMode/drain, so -g and -src behave identically in both modes.
+// It exits on SIGINT, printing a final summary first.
+func runFollow(src LogSource, since string, limit int, all, important, errorsOnly bool, fpMode FingerprintMode, drain *drainTree, renderer Renderer, versionText, nameText string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT)
+	defer signal.Stop(sigCh)
+
+	entries, err := src.Read(ctx)
+	if err != nil {
+		fmt.Printf("source error: %v\n", err)
+		return
+	}
+
+	var mu sync.Mutex
+	groups := map[string]*MessageGroup{}
+
+	// merger goroutine: mutate the shared group map under mu
+	go func() {
+		for e := range entries {
+			fp, template := fingerprint(e.Message, fpMode, drain)
+			mu.Lock()
+			if g, ok := groups[fp]; ok {
+				g.Count++
+				g.Times = append(g.Times, e.Time)
+				if template != "" {
+					g.Template = template
+				}
+			} else {
+				groups[fp] = &MessageGroup{Sample: e.Message, Template: template, Count: 1, Level: e.Level, Times: []time.Time{e.Time}}
+			}
+			mu.Unlock()
+		}
+	}()
+
+	title := fmt.Sprintf("#[--- LogWizzy Live (top %d) (since %s) ---]#\n", limit, since)
+
+	// Gated on stdout being a TTY, same as the spinner, so cron/systemd
+	// invocations don't fill logs with screen-clear escapes every tick.
+	interactive := isTerminal(os.Stdout)
+
+	redraw := func() {
+		mu.Lock()
+		list := make([]*MessageGroup, 0, len(groups))
+		for _, g := range groups {
+			list = append(list, g)
+		}
+		mu.Unlock()
+
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Count == list[j].Count {
+				return list[i].Sample < list[j].Sample
+			}
+			return list[i].Count > list[j].Count
+		})
+
+		if interactive {
+			fmt.Printf("\033[H\033[2J") // cursor home, clear screen
+		}
+		renderer.Header(versionText, nameText, title)
+		shown := 0
+		for _, g := range list {
+			if errorsOnly && !(g.Level == "CRIT" || g.Level == "ERRO") {
+				continue
+			}
+			if important && !(g.Level == "CRIT" || g.Level == "ERRO" || g.Level == "WARN") {
+				continue
+			}
+			if !all && !important && !errorsOnly && shown >= limit {
+				break
+			}
+			renderer.Group(g)
+			shown++
+		}
+	}
+
+	// Non-interactive output (redirected to a file, piped, cron/systemd)
+	// skips the periodic redraw cycle entirely and just prints the final
+	// summary once, the same way a plain sequential log report would.
+	if !interactive {
+		<-sigCh
+		cancel()
+		redraw()
+		renderer.Footer("LogWizzy Done!")
+		return
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigCh:
+			cancel()
+			redraw()
+			renderer.Footer("LogWizzy Done!")
+			return
+		case <-ticker.C:
+			redraw()
+		}
+	}
+}
@@ -1,11 +1,11 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"os/exec"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,13 +14,16 @@ import (
 
 const Version = "0.2"
 
-// MessageGroup represents a group of identical log messages
-// storing sample text, count of occurrences, log level, and timestamps.
+// MessageGroup represents a group of identical (or fuzzily-matched) log
+// messages storing sample text, count of occurrences, log level, and
+// timestamps. Template holds the templatized form produced by drain
+// fingerprinting, alongside the raw first-seen Sample text.
 type MessageGroup struct {
-	Sample string
-	Count  int
-	Level  string
-	Times  []time.Time
+	Sample   string
+	Template string
+	Count    int
+	Level    string
+	Times    []time.Time
 }
 
 // mapPriority maps journalctl numeric/text PRIORITY values to human-readable log levels.
@@ -96,6 +99,38 @@ func parseInt64(s string) (int64, error) {
 	return v, nil
 }
 
+// parseJournalLine parses a single line of `journalctl -o json` output into
+// its message, level, and timestamp. ok is false if the line isn't usable
+// (blank or not valid JSON).
+func parseJournalLine(line string) (msg, level string, ts time.Time, ok bool) {
+	if strings.TrimSpace(line) == "" {
+		return "", "", time.Time{}, false
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return "", "", time.Time{}, false
+	}
+
+	if m, ok := raw["MESSAGE"].(string); ok {
+		msg = m
+	}
+
+	level = "UNKN"
+	if p, ok := raw["PRIORITY"].(string); ok {
+		level = mapPriority(p)
+	}
+
+	ts = time.Now()
+	if tStr, ok := raw["__REALTIME_TIMESTAMP"].(string); ok {
+		if tInt, err := parseTimestamp(tStr); err == nil {
+			ts = tInt
+		}
+	}
+
+	return msg, level, ts, true
+}
+
 func main() {
 	// -------------------------------
 	// CLI Flags
@@ -106,6 +141,15 @@ func main() {
 	all := flag.Bool("a", false, "Show all logs without limit")
 	important := flag.Bool("i", false, "Show only important logs (CRIT, ERRO, WARN)")
 	errorsOnly := flag.Bool("e", false, "Show only errors (CRIT + ERRO)")
+	output := flag.String("o", "text", "Output mode: text, json, or logfmt")
+	follow := flag.Bool("f", false, "Follow mode: stream the -src log source with live aggregation")
+	flag.BoolVar(follow, "follow", false, "Alias for -f")
+	group := flag.String("g", "exact", "Grouping mode: exact, normalized, or drain")
+	source := flag.String("src", "journalctl", "Log source: journalctl, file:<glob>, docker:<name>, k8s:<selector>, syslog:<proto>://<addr>")
+	color := flag.String("color", "auto", "Color mode: auto, always, or never")
+	alert := flag.Bool("alert", false, "Alert mode: exit non-zero and dispatch webhooks when thresholds are exceeded")
+	alertCount := flag.String("alert-count", "", "Count thresholds, e.g. 'ERRO>=5,CRIT>=1'")
+	alertRate := flag.String("alert-rate", "", "Rate thresholds, e.g. 'WARN>10/min'")
 
 	defaultLimit := 10    // default number of logs to display
 	limit := defaultLimit // store limit value
@@ -124,6 +168,10 @@ func main() {
 
 	flag.Parse()
 
+	outputMode := OutputMode(strings.ToLower(*output))
+	colorOn := colorEnabled(parseColorMode(*color))
+	renderer := newRenderer(outputMode, colorOn)
+
 	versionText := fmt.Sprintf("LogWizzy %s", Version)
 	nameText := "Made by Knuspii, (M)"
 
@@ -134,11 +182,19 @@ func main() {
 		fmt.Printf("#[--- LogWizzy Help ---]#\n%s\n%s\n\nUsage:\n  logwizzy [options]\n\nOptions:\n", versionText, nameText)
 		fmt.Printf("  -s VALUE   Set start time for logs (default: today)\n")
 		fmt.Printf("  -l VALUE   Number of log entries to show (default 10)\n")
+		fmt.Printf("  -o VALUE   Output mode: text, json, or logfmt (default text)\n")
 		fmt.Printf("  -v         Show version and exit\n")
 		fmt.Printf("  -h         Show help\n")
 		fmt.Printf("  -a         Show all logs without limit\n")
 		fmt.Printf("  -i         Show only important logs (CRIT, ERRO, WARN)\n")
 		fmt.Printf("  -e         Show only errors (CRIT + ERRO)\n")
+		fmt.Printf("  -f         Follow mode: live-updating summary (Ctrl+C to stop)\n")
+		fmt.Printf("  -g VALUE   Grouping mode: exact, normalized, or drain (default exact)\n")
+		fmt.Printf("  -src VALUE Log source: journalctl, file:<glob>, docker:<name>, k8s:<selector>, syslog:<proto>://<addr>\n")
+		fmt.Printf("  -color VALUE Color mode: auto, always, or never (default auto)\n")
+		fmt.Printf("  -alert        Exit non-zero and dispatch webhooks when thresholds are exceeded\n")
+		fmt.Printf("  -alert-count VALUE  Count thresholds, e.g. 'ERRO>=5,CRIT>=1'\n")
+		fmt.Printf("  -alert-rate VALUE   Rate thresholds, e.g. 'WARN>10/min'\n")
 		return
 	}
 	if *showVersion {
@@ -149,8 +205,6 @@ func main() {
 	// -------------------------------
 	// Print Header
 	// -------------------------------
-	fmt.Printf("%s\n%s\n", versionText, nameText)
-
 	title := fmt.Sprintf("#[--- LogWizzy Summary (top %d) (since %s) ---]#", limit, *since)
 	if *errorsOnly {
 		title = fmt.Sprintf("#[--- LogWizzy Errors Only (since %s) ---]#", *since)
@@ -159,71 +213,71 @@ func main() {
 	} else if *all {
 		title = fmt.Sprintf("#[--- LogWizzy Full Log Dump (since %s) ---]#", *since)
 	}
-	fmt.Printf(title)
+	if *follow {
+		renderer.Header(versionText, nameText, title+"\n")
+		src, err := newLogSource(*source, *since, true)
+		if err != nil {
+			fmt.Printf("source error: %v\n", err)
+			return
+		}
+		fpMode := FingerprintMode(strings.ToLower(*group))
+		var drain *drainTree
+		if fpMode == FingerprintDrain {
+			drain = newDrainTree()
+		}
+		runFollow(src, *since, limit, *all, *important, *errorsOnly, fpMode, drain, renderer, versionText, nameText)
+		return
+	}
+	renderer.Header(versionText, nameText, title)
 
 	// -------------------------------
 	// Start spinner animation
 	// -------------------------------
+	// Gated on stdout being a TTY so cron/systemd invocations don't fill
+	// logs with \r garbage.
+	showSpinner := outputMode == OutputText && isTerminal(os.Stdout)
 	done := make(chan bool)
-	go spinner(done)
+	if showSpinner {
+		go spinner(done)
+	}
 
 	// -------------------------------
-	// Run journalctl command
+	// Open the log source
 	// -------------------------------
-	args := []string{"-o", "json", "--since=" + *since}
-	cmd := exec.Command("journalctl", args...)
-	stdout, _ := cmd.StdoutPipe()
-	stderr, _ := cmd.StderrPipe()
-	cmd.Start()
-
-	// Separate goroutine to handle journalctl stderr
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			fmt.Printf("\njournalctl error: %s\n", scanner.Text())
-		}
-	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := newLogSource(*source, *since, false)
+	if err != nil {
+		fmt.Printf("source error: %v\n", err)
+		return
+	}
+	entries, err := src.Read(ctx)
+	if err != nil {
+		fmt.Printf("source error: %v\n", err)
+		return
+	}
 
 	// -------------------------------
 	// Parse logs into message groups
 	// -------------------------------
-	groups := map[string]*MessageGroup{}
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-
-		var raw map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &raw); err != nil {
-			continue
-		}
-
-		msg := ""
-		if m, ok := raw["MESSAGE"].(string); ok {
-			msg = m
-		}
-
-		pri := "UNKN"
-		if p, ok := raw["PRIORITY"].(string); ok {
-			pri = mapPriority(p)
-		}
-
-		ts := time.Now()
-		if tStr, ok := raw["__REALTIME_TIMESTAMP"].(string); ok {
-			if tInt, err := parseTimestamp(tStr); err == nil {
-				ts = tInt
-			}
-		}
+	fpMode := FingerprintMode(strings.ToLower(*group))
+	var drain *drainTree
+	if fpMode == FingerprintDrain {
+		drain = newDrainTree()
+	}
 
-		// group messages by exact text
-		fp := msg
+	groups := map[string]*MessageGroup{}
+	for e := range entries {
+		fp, template := fingerprint(e.Message, fpMode, drain)
 		if g, ok := groups[fp]; ok {
 			g.Count++
-			g.Times = append(g.Times, ts)
+			g.Times = append(g.Times, e.Time)
+			if template != "" {
+				g.Template = template
+			}
 		} else {
-			groups[fp] = &MessageGroup{Sample: msg, Count: 1, Level: pri, Times: []time.Time{ts}}
+			groups[fp] = &MessageGroup{Sample: e.Message, Template: template, Count: 1, Level: e.Level, Times: []time.Time{e.Time}}
 		}
 	}
 
@@ -259,8 +313,10 @@ func main() {
 		return list[i].Count > list[j].Count
 	})
 
-	done <- true
-	fmt.Printf("\r\033[K") // clear spinner line
+	if showSpinner {
+		done <- true
+		fmt.Printf("\r\033[K") // clear spinner line
+	}
 
 	// -------------------------------
 	// Print logs
@@ -285,29 +341,49 @@ func main() {
 			break
 		}
 
-		color := colorForLevel(g.Level)
-		reset := "\033[0m"
-		fmt.Printf("%s[%s] %dx %s%s\n", color, g.Level, g.Count, g.Sample, reset)
-		fmt.Printf("---\n")
+		renderer.Group(g)
 		shown++
 	}
 
 	// -------------------------------
 	// Extra: Show all errors at the end in default mode
-	// Only if user did not set -l manually
+	// Only if user did not set -l manually. This recap is a human-facing
+	// convenience for TextRenderer; structured modes already emitted every
+	// group once above, so repeating them here would double-count for a
+	// downstream jq/Loki/Elastic consumer.
+	// -------------------------------
+	if outputMode == OutputText && !*errorsOnly && !*important && !*all && !limitSet {
+		renderer.Section(fmt.Sprintf("#[--- Additional Errors (since %s) ---]#\n", *since), errorsList)
+	}
+
+	renderer.Footer("LogWizzy Done!")
+
 	// -------------------------------
-	if !*errorsOnly && !*important && !*all && !limitSet {
-		fmt.Printf("#[--- Additional Errors (since %s) ---]#\n", *since)
-		for _, g := range list {
-			if g.Level == "CRIT" || g.Level == "ERRO" {
-				color := colorForLevel(g.Level)
-				reset := "\033[0m"
-				fmt.Printf("%s[%s] %dx %s%s\n", color, g.Level, g.Count, g.Sample, reset)
-				fmt.Printf("---\n")
+	// Evaluate alert thresholds
+	// -------------------------------
+	alertFired := false
+	if *alert {
+		rules := append(parseCountRules(*alertCount), parseRateRules(*alertRate)...)
+		if cfgPath, err := alertsConfigPath(); err == nil {
+			if cfgRules, err := loadAlertConfig(cfgPath); err == nil {
+				rules = append(rules, cfgRules...)
+			}
+		}
+
+		for _, rule := range rules {
+			value, fired := evalAlertRule(rule, list)
+			if !fired {
+				continue
+			}
+			alertFired = true
+			fmt.Printf("ALERT %q fired: %s %s %g (observed %.2f)\n", rule.Name, rule.Metric, rule.Op, rule.Threshold, value)
+			if err := dispatchAlert(rule, value); err != nil {
+				fmt.Printf("alert dispatch error: %v\n", err)
 			}
 		}
 	}
 
-	cmd.Wait()
-	fmt.Println("LogWizzy Done!")
+	if alertFired {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutputMode selects how message groups are written to stdout.
+type OutputMode string
+
+const (
+	OutputText   OutputMode = "text"
+	OutputJSON   OutputMode = "json"
+	OutputLogfmt OutputMode = "logfmt"
+)
+
+// Renderer writes message groups to stdout in a specific format. Adding a new
+// output mode means adding a new Renderer, not touching the aggregation code.
+type Renderer interface {
+	// Header prints the banner/version/title block before any groups.
+	Header(versionText, nameText, title string)
+	// Group prints a single message group.
+	Group(g *MessageGroup)
+	// Section prints a titled block of groups, e.g. the trailing errors appendix.
+	Section(title string, groups []*MessageGroup)
+	// Footer prints any closing text once every group has been rendered.
+	Footer(text string)
+}
+
+// newRenderer selects a Renderer for the given output mode, defaulting to
+// text. color only affects TextRenderer; structured modes are never colored.
+func newRenderer(mode OutputMode, color bool) Renderer {
+	switch mode {
+	case OutputJSON:
+		return &JSONRenderer{}
+	case OutputLogfmt:
+		return &LogfmtRenderer{}
+	default:
+		return &TextRenderer{Color: color}
+	}
+}
+
+// groupRecord is the structured form of a MessageGroup shared by JSONRenderer
+// and LogfmtRenderer.
+type groupRecord struct {
+	Level     string   `json:"level"`
+	Count     int      `json:"count"`
+	Sample    string   `json:"sample"`
+	Template  string   `json:"template,omitempty"`
+	FirstSeen string   `json:"first_seen"`
+	LastSeen  string   `json:"last_seen"`
+	Times     []string `json:"times"`
+}
+
+// toRecord converts a MessageGroup into its structured record form. Times
+// are already in the order encountered, so the first and last entries are
+// the first/last seen timestamps.
+func toRecord(g *MessageGroup) groupRecord {
+	r := groupRecord{Level: g.Level, Count: g.Count, Sample: g.Sample, Template: g.Template}
+	for _, t := range g.Times {
+		r.Times = append(r.Times, t.Format(time.RFC3339Nano))
+	}
+	if len(g.Times) > 0 {
+		r.FirstSeen = g.Times[0].Format(time.RFC3339Nano)
+		r.LastSeen = g.Times[len(g.Times)-1].Format(time.RFC3339Nano)
+	}
+	return r
+}
+
+// TextRenderer reproduces LogWizzy's original ANSI-colored terminal output.
+// Color is false when colors have been disabled (--color=never, NO_COLOR,
+// or stdout isn't a TTY), in which case no escape codes are emitted.
+type TextRenderer struct {
+	Color bool
+}
+
+func (TextRenderer) Header(versionText, nameText, title string) {
+	fmt.Printf("%s\n%s\n", versionText, nameText)
+	fmt.Printf(title)
+}
+
+func (t TextRenderer) Group(g *MessageGroup) {
+	color, reset := "", ""
+	if t.Color {
+		color = colorForLevel(g.Level)
+		reset = "\033[0m"
+	}
+	fmt.Printf("%s[%s] %dx %s%s\n", color, g.Level, g.Count, g.Sample, reset)
+	fmt.Printf("---\n")
+}
+
+func (t TextRenderer) Section(title string, groups []*MessageGroup) {
+	fmt.Printf(title)
+	for _, g := range groups {
+		t.Group(g)
+	}
+}
+
+func (TextRenderer) Footer(text string) {
+	fmt.Println(text)
+}
+
+// JSONRenderer emits each message group as a single-line JSON object, so
+// LogWizzy output can be piped into jq, Loki, or Elastic.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Header(versionText, nameText, title string) {}
+
+func (JSONRenderer) Group(g *MessageGroup) {
+	b, err := json.Marshal(toRecord(g))
+	if err != nil {
+		return
+	}
+	fmt.Println(string(b))
+}
+
+func (r JSONRenderer) Section(title string, groups []*MessageGroup) {
+	for _, g := range groups {
+		r.Group(g)
+	}
+}
+
+func (JSONRenderer) Footer(text string) {}
+
+// LogfmtRenderer emits each message group as a logfmt (key=value) line.
+type LogfmtRenderer struct{}
+
+func (LogfmtRenderer) Header(versionText, nameText, title string) {}
+
+func (LogfmtRenderer) Group(g *MessageGroup) {
+	rec := toRecord(g)
+	fmt.Printf("level=%s count=%d sample=%s", rec.Level, rec.Count, logfmtValue(rec.Sample))
+	if rec.Template != "" {
+		fmt.Printf(" template=%s", logfmtValue(rec.Template))
+	}
+	fmt.Printf(" first_seen=%s last_seen=%s times=%s\n",
+		rec.FirstSeen, rec.LastSeen, logfmtValue(strings.Join(rec.Times, ",")))
+}
+
+func (r LogfmtRenderer) Section(title string, groups []*MessageGroup) {
+	for _, g := range groups {
+		r.Group(g)
+	}
+}
+
+func (LogfmtRenderer) Footer(text string) {}
+
+// logfmtValue quotes a value if it contains characters that would make the
+// key=value pair ambiguous, or any control character (notably \n or \r,
+// which would otherwise split a record across multiple lines of the
+// line-oriented logfmt stream).
+func logfmtValue(s string) string {
+	needsQuote := strings.ContainsAny(s, " =\"")
+	if !needsQuote {
+		for _, r := range s {
+			if r < 0x20 || r == 0x7f {
+				needsQuote = true
+				break
+			}
+		}
+	}
+	if needsQuote {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout runs fn with os.Stdout redirected to a pipe and returns
+// everything written to it.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = orig
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading captured stdout: %v", err)
+	}
+	return string(out)
+}
+
+func TestLogfmtValueQuoting(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain token", "foo", "foo"},
+		{"contains space", "foo bar", `"foo bar"`},
+		{"contains equals", "foo=bar", `"foo=bar"`},
+		{"contains quote", `foo"bar`, `"foo\"bar"`},
+		{"contains newline", "foo\nbar", "\"foo\\nbar\""},
+		{"contains carriage return", "foo\rbar", "\"foo\\rbar\""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := logfmtValue(c.input)
+			if got != c.want {
+				t.Errorf("logfmtValue(%q) = %q, want %q", c.input, got, c.want)
+			}
+			if strings.ContainsAny(got, "\n\r") {
+				t.Errorf("logfmtValue(%q) = %q still contains a raw newline/CR", c.input, got)
+			}
+		})
+	}
+}
+
+func TestJSONRendererHeaderFooterAreSilent(t *testing.T) {
+	// JSON/logfmt output is meant to be piped into jq/Loki/Elastic; the
+	// banner/footer text that TextRenderer prints would corrupt that stream,
+	// so those hooks must stay no-ops.
+	r := JSONRenderer{}
+	out := captureStdout(t, func() {
+		r.Header("v", "n", "title")
+		r.Footer("done")
+	})
+	if out != "" {
+		t.Errorf("JSONRenderer.Header/Footer wrote %q, want no output", out)
+	}
+}
+
+func TestLogfmtRendererHeaderFooterAreSilent(t *testing.T) {
+	r := LogfmtRenderer{}
+	out := captureStdout(t, func() {
+		r.Header("v", "n", "title")
+		r.Footer("done")
+	})
+	if out != "" {
+		t.Errorf("LogfmtRenderer.Header/Footer wrote %q, want no output", out)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a single log line normalized from any LogSource.
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+}
+
+// LogSource produces a stream of normalized log Entries from some
+// substrate (journalctl, files, Docker, Kubernetes, syslog). The
+// aggregation, sorting, and rendering pipeline in main doesn't care which
+// LogSource it's reading from.
+type LogSource interface {
+	Read(ctx context.Context) (<-chan Entry, error)
+}
+
+// newLogSource builds a LogSource from a -src flag value of the form
+// "journalctl" (the default), "file:<glob>", "docker:<container>",
+// "k8s:<selector>", or "syslog:<proto>://<addr>".
+func newLogSource(spec, since string, follow bool) (LogSource, error) {
+	if spec == "" || spec == "journalctl" {
+		return JournalctlSource{Since: since, Follow: follow}, nil
+	}
+	kind, rest, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid -src %q: expected kind:value", spec)
+	}
+	switch kind {
+	case "file":
+		return FileSource{Pattern: rest, Follow: follow}, nil
+	case "docker":
+		return DockerSource{Container: rest, Follow: follow}, nil
+	case "k8s":
+		return K8sSource{Selector: rest, Follow: follow}, nil
+	case "syslog":
+		proto, addr, ok := strings.Cut(rest, "://")
+		if !ok {
+			return nil, fmt.Errorf("invalid -src syslog value %q: expected proto://addr", rest)
+		}
+		if !follow {
+			return nil, fmt.Errorf("-src syslog:%s only supports follow mode; add -f", rest)
+		}
+		return SyslogSource{Proto: proto, Addr: addr}, nil
+	default:
+		return nil, fmt.Errorf("unknown -src kind %q", kind)
+	}
+}
+
+// runCommandSource starts cmd, forwards its stderr to stdout prefixed with
+// label (matching LogWizzy's existing "journalctl error: ..." behavior),
+// and parses each stdout line with parse into the returned channel.
+func runCommandSource(cmd *exec.Cmd, label string, parse func(line string) (Entry, bool)) (<-chan Entry, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var stderrDone sync.WaitGroup
+	stderrDone.Add(1)
+	go func() {
+		defer stderrDone.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			fmt.Printf("\n%s error: %s\n", label, scanner.Text())
+		}
+	}()
+
+	out := make(chan Entry, 256)
+	go func() {
+		defer close(out)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if e, ok := parse(scanner.Text()); ok {
+				out <- e
+			}
+		}
+		stderrDone.Wait()
+		cmd.Wait()
+	}()
+
+	return out, nil
+}
+
+// JournalctlSource reads `journalctl -o json`, LogWizzy's original and
+// default log source.
+type JournalctlSource struct {
+	Since  string
+	Follow bool
+}
+
+func (s JournalctlSource) Read(ctx context.Context) (<-chan Entry, error) {
+	args := []string{"-o", "json", "--since=" + s.Since}
+	if s.Follow {
+		args = append(args, "-f")
+	}
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	return runCommandSource(cmd, "journalctl", func(line string) (Entry, bool) {
+		msg, level, ts, ok := parseJournalLine(line)
+		if !ok {
+			return Entry{}, false
+		}
+		return Entry{Time: ts, Level: level, Message: msg}, true
+	})
+}
+
+// DockerSource reads `docker logs` for a single container.
+type DockerSource struct {
+	Container string
+	Follow    bool
+}
+
+func (s DockerSource) Read(ctx context.Context) (<-chan Entry, error) {
+	args := []string{"logs", "--timestamps"}
+	if s.Follow {
+		args = append(args, "--follow")
+	}
+	args = append(args, s.Container)
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	return runCommandSource(cmd, "docker", parsePlainLine)
+}
+
+// K8sSource reads `kubectl logs` across every pod matching a selector.
+type K8sSource struct {
+	Selector string
+	Follow   bool
+}
+
+func (s K8sSource) Read(ctx context.Context) (<-chan Entry, error) {
+	args := []string{"logs", "-l", s.Selector, "--all-containers", "--timestamps"}
+	if s.Follow {
+		args = append(args, "--follow")
+	}
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	return runCommandSource(cmd, "kubectl", parsePlainLine)
+}
+
+// parsePlainLine parses a line of docker/kubectl log output: plain text,
+// optionally prefixed by an RFC3339Nano timestamp (as produced by
+// --timestamps), with no structured level information.
+func parsePlainLine(line string) (Entry, bool) {
+	if strings.TrimSpace(line) == "" {
+		return Entry{}, false
+	}
+	if parts := strings.SplitN(line, " ", 2); len(parts) == 2 {
+		if ts, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+			return Entry{Time: ts, Level: "UNKN", Message: parts[1]}, true
+		}
+	}
+	return Entry{Time: time.Now(), Level: "UNKN", Message: line}, true
+}
+
+// FileSource reads one or more files matched by a glob pattern, with tail
+// semantics: when Follow is set it keeps polling each file for appended
+// lines instead of stopping at EOF.
+type FileSource struct {
+	Pattern string
+	Follow  bool
+}
+
+func (s FileSource) Read(ctx context.Context) (<-chan Entry, error) {
+	paths, err := filepath.Glob(s.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match %q", s.Pattern)
+	}
+
+	out := make(chan Entry, 256)
+	var wg sync.WaitGroup
+	for _, p := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			tailFile(ctx, path, s.Follow, out)
+		}(p)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+// tailFile reads path from the start. With follow set, it keeps polling
+// for appended lines (tail -f semantics) until ctx is cancelled; otherwise
+// it stops at EOF.
+func tailFile(ctx context.Context, path string, follow bool, out chan<- Entry) {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("\nfile error: %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			out <- Entry{Time: time.Now(), Level: "UNKN", Message: strings.TrimRight(line, "\n")}
+		}
+		if err != nil {
+			if !follow {
+				return
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+}
+
+// SyslogSource listens for RFC5424 syslog messages over UDP or TCP.
+type SyslogSource struct {
+	Proto string // "udp" or "tcp"
+	Addr  string
+}
+
+var reSyslog5424 = regexp.MustCompile(`^<(\d+)>\d+ \S+ \S+ \S+ \S+ \S+ (?:-|\[.*?\])(?: (.*))?$`)
+
+func (s SyslogSource) Read(ctx context.Context) (<-chan Entry, error) {
+	out := make(chan Entry, 256)
+	switch s.Proto {
+	case "udp":
+		addr, err := net.ResolveUDPAddr("udp", s.Addr)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.ListenUDP("udp", addr)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer close(out)
+			defer conn.Close()
+			go func() { <-ctx.Done(); conn.Close() }()
+			buf := make([]byte, 64*1024)
+			for {
+				n, _, err := conn.ReadFromUDP(buf)
+				if err != nil {
+					return
+				}
+				if e, ok := parseSyslogLine(string(buf[:n])); ok {
+					out <- e
+				}
+			}
+		}()
+	case "tcp":
+		ln, err := net.Listen("tcp", s.Addr)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer close(out)
+			defer ln.Close()
+			go func() { <-ctx.Done(); ln.Close() }()
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				go func(c net.Conn) {
+					defer c.Close()
+					scanner := bufio.NewScanner(c)
+					for scanner.Scan() {
+						if e, ok := parseSyslogLine(scanner.Text()); ok {
+							out <- e
+						}
+					}
+				}(conn)
+			}
+		}()
+	default:
+		return nil, fmt.Errorf("unsupported syslog protocol %q", s.Proto)
+	}
+	return out, nil
+}
+
+// parseSyslogLine parses an RFC5424 syslog line:
+// <PRI>VERSION TIMESTAMP HOST APP PROCID MSGID [SD] MSG
+func parseSyslogLine(line string) (Entry, bool) {
+	m := reSyslog5424.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, false
+	}
+	pri, _ := strconv.Atoi(m[1])
+	return Entry{Time: time.Now(), Level: syslogSeverityLevel(pri % 8), Message: m[2]}, true
+}
+
+// syslogSeverityLevel maps an RFC5424 severity (0-7) to LogWizzy's levels.
+func syslogSeverityLevel(sev int) string {
+	switch {
+	case sev <= 2:
+		return "CRIT"
+	case sev == 3:
+		return "ERRO"
+	case sev == 4:
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}